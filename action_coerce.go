@@ -0,0 +1,204 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/juju/errors"
+)
+
+// Coerce validates params the same way ValidateParams does, but first
+// coerces it into the shape the schema expects: map[interface{}]interface{}
+// values are normalized to map[string]interface{} (via the same cleanse
+// helper ReadActionsYaml uses), values are converted to the Go type their
+// schema "type" declares (e.g. a numeric string becomes an int where the
+// schema says "integer"), missing fields gain their schema "default"
+// value, and unknown fields are dropped wherever the schema sets
+// "additionalProperties: false". It returns the coerced, fully-typed
+// params, so callers no longer need to marshal/unmarshal through JSON
+// themselves to get the same effect.
+func (spec *ActionSpec) Coerce(params interface{}) (interface{}, error) {
+	cleansed, err := cleanse(params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	coerced, err := coerceValue(spec.Params, cleansed)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if ok, err := spec.ValidateParams(coerced); !ok {
+		return nil, err
+	}
+	return coerced, nil
+}
+
+// coerceValue coerces value to the Go type schema's "type" keyword
+// declares, recursing into "properties" and "items" as needed.
+func coerceValue(schema map[string]interface{}, value interface{}) (interface{}, error) {
+	if value == nil {
+		if def, ok := schema["default"]; ok {
+			return def, nil
+		}
+		if schema["type"] == "object" {
+			// No value was supplied for this object at all (e.g. the
+			// action was invoked with no params); still recurse so any
+			// defaults on its individual properties get applied.
+			return coerceObject(schema, map[string]interface{}{})
+		}
+		return nil, nil
+	}
+
+	switch schema["type"] {
+	case "object":
+		return coerceObject(schema, value)
+	case "array":
+		return coerceArray(schema, value)
+	case "integer":
+		return coerceInt(value)
+	case "number":
+		return coerceNumber(value)
+	case "boolean":
+		return coerceBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// coerceObject coerces value's known properties, applies defaults for any
+// that are missing, and drops unknown properties when schema disallows
+// them via "additionalProperties: false".
+func coerceObject(schema map[string]interface{}, value interface{}) (interface{}, error) {
+	asMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected a map, got %T", value)
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	additionalAllowed := true
+	if allowed, ok := schema["additionalProperties"].(bool); ok {
+		additionalAllowed = allowed
+	}
+
+	result := make(map[string]interface{})
+	for name, raw := range asMap {
+		propSchema, known := properties[name].(map[string]interface{})
+		if !known {
+			if additionalAllowed {
+				result[name] = raw
+			}
+			continue
+		}
+		coerced, err := coerceValue(propSchema, raw)
+		if err != nil {
+			return nil, errors.Annotatef(err, "param %q", name)
+		}
+		result[name] = coerced
+	}
+
+	for name, propRaw := range properties {
+		if _, ok := result[name]; ok {
+			continue
+		}
+		propSchema, ok := propRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// Recurse rather than only checking propSchema["default"], so
+		// defaults declared on a missing object's own sub-properties are
+		// applied the same way they would be for an explicit nil value.
+		coerced, err := coerceValue(propSchema, nil)
+		if err != nil {
+			return nil, errors.Annotatef(err, "param %q", name)
+		}
+		if coerced != nil {
+			result[name] = coerced
+		}
+	}
+
+	return result, nil
+}
+
+// coerceArray coerces each element of value against schema's "items".
+func coerceArray(schema map[string]interface{}, value interface{}) (interface{}, error) {
+	asSlice, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected a list, got %T", value)
+	}
+
+	items, _ := schema["items"].(map[string]interface{})
+	if items == nil {
+		return asSlice, nil
+	}
+
+	result := make([]interface{}, len(asSlice))
+	for i, elem := range asSlice {
+		coerced, err := coerceValue(items, elem)
+		if err != nil {
+			return nil, errors.Annotatef(err, "item %d", i)
+		}
+		result[i] = coerced
+	}
+	return result, nil
+}
+
+// coerceInt coerces value to an int.
+func coerceInt(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case int:
+		return typed, nil
+	case int64:
+		return int(typed), nil
+	case float64:
+		if typed != math.Trunc(typed) {
+			return nil, errors.Errorf("expected an integer, got %v", typed)
+		}
+		return int(typed), nil
+	case string:
+		n, err := strconv.Atoi(typed)
+		if err != nil {
+			return nil, errors.Errorf("expected an integer, got %q", typed)
+		}
+		return n, nil
+	default:
+		return nil, errors.Errorf("expected an integer, got %T", value)
+	}
+}
+
+// coerceNumber coerces value to a float64.
+func coerceNumber(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, nil
+	case int:
+		return float64(typed), nil
+	case string:
+		n, err := strconv.ParseFloat(typed, 64)
+		if err != nil {
+			return nil, errors.Errorf("expected a number, got %q", typed)
+		}
+		return n, nil
+	default:
+		return nil, errors.Errorf("expected a number, got %T", value)
+	}
+}
+
+// coerceBool coerces value to a bool.
+func coerceBool(value interface{}) (interface{}, error) {
+	switch typed := value.(type) {
+	case bool:
+		return typed, nil
+	case string:
+		b, err := strconv.ParseBool(typed)
+		if err != nil {
+			return nil, errors.Errorf("expected a boolean, got %q", typed)
+		}
+		return b, nil
+	default:
+		return nil, errors.Errorf("expected a boolean, got %T", value)
+	}
+}
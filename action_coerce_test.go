@@ -0,0 +1,91 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoerceAppliesDefaultsToOmittedNestedObject(t *testing.T) {
+	spec := &ActionSpec{
+		Params: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"nested": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"timeout": map[string]interface{}{
+							"type":    "integer",
+							"default": 30,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := spec.Coerce(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"nested": map[string]interface{}{"timeout": 30},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestCoerceOmittedAndExplicitNullAgree(t *testing.T) {
+	spec := &ActionSpec{
+		Params: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"nested": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"timeout": map[string]interface{}{
+							"type":    "integer",
+							"default": 30,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	omitted, err := spec.Coerce(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	explicit, err := spec.Coerce(map[string]interface{}{"nested": nil})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(omitted, explicit) {
+		t.Errorf("omitted %#v and explicit-null %#v should agree", omitted, explicit)
+	}
+}
+
+func TestCoerceIntFromString(t *testing.T) {
+	spec := &ActionSpec{
+		Params: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"timeout": map[string]interface{}{"type": "integer"},
+			},
+		},
+	}
+
+	got, err := spec.Coerce(map[string]interface{}{"timeout": "42"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{"timeout": 42}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
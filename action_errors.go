@@ -0,0 +1,41 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a single JSON-Schema validation failure
+// encountered while checking action params against an ActionSpec's schema.
+type ValidationError struct {
+	// Field is the JSON-Schema field path that failed, e.g. "timeout" or
+	// "target.host" ("(root)" for a failure on the params object itself).
+	Field string
+
+	// Keyword is the JSON-Schema keyword that was violated, e.g. "type".
+	Keyword string
+
+	// Message is a human-readable description of the failure.
+	Message string
+}
+
+// Error implements error.
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Field, e.Message, e.Keyword)
+}
+
+// ValidationErrors collects every ValidationError produced by a single
+// ValidateParams call.
+type ValidationErrors []ValidationError
+
+// Error implements error.
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,66 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"regexp"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func init() {
+	RegisterActionFormatChecker("juju-unit-name", unitNameFormatChecker{})
+	RegisterActionFormatChecker("juju-application-name", applicationNameFormatChecker{})
+	RegisterActionFormatChecker("juju-relation-id", relationIDFormatChecker{})
+}
+
+// RegisterActionFormatChecker adds a JSON-Schema "format" checker that an
+// action's params schema can reference via "format": name. This lets charm
+// authors validate domain-specific strings (durations, ports, juju unit
+// names, CIDRs, and so on) beyond the formats Draft 7 defines itself.
+func RegisterActionFormatChecker(name string, checker gojsonschema.FormatChecker) {
+	gojsonschema.FormatCheckers.Add(name, checker)
+}
+
+var (
+	unitNameRe        = regexp.MustCompile(`^[a-z](?:[a-z0-9-]*[a-z0-9])?/\d+$`)
+	applicationNameRe = regexp.MustCompile(`^[a-z](?:[a-z0-9-]*[a-z0-9])?$`)
+	relationIDRe      = regexp.MustCompile(`^[a-z](?:[a-z0-9-]*[a-z0-9])?:\d+$`)
+)
+
+// unitNameFormatChecker validates strings of the form "application/0".
+type unitNameFormatChecker struct{}
+
+// IsFormat implements gojsonschema.FormatChecker.
+func (unitNameFormatChecker) IsFormat(input interface{}) bool {
+	str, ok := input.(string)
+	if !ok {
+		return false
+	}
+	return unitNameRe.MatchString(str)
+}
+
+// applicationNameFormatChecker validates juju application names.
+type applicationNameFormatChecker struct{}
+
+// IsFormat implements gojsonschema.FormatChecker.
+func (applicationNameFormatChecker) IsFormat(input interface{}) bool {
+	str, ok := input.(string)
+	if !ok {
+		return false
+	}
+	return applicationNameRe.MatchString(str)
+}
+
+// relationIDFormatChecker validates strings of the form "application:42".
+type relationIDFormatChecker struct{}
+
+// IsFormat implements gojsonschema.FormatChecker.
+func (relationIDFormatChecker) IsFormat(input interface{}) bool {
+	str, ok := input.(string)
+	if !ok {
+		return false
+	}
+	return relationIDRe.MatchString(str)
+}
@@ -8,15 +8,23 @@ import (
 	"io"
 	"io/ioutil"
 	"regexp"
-	"strings"
 
 	"github.com/juju/errors"
-	"github.com/juju/gojsonschema"
+	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v1"
 )
 
 var prohibitedSchemaKeys = map[string]bool{"$ref": true, "$schema": true}
 
+// whitelistedSchemaRefs are the only external schema documents an
+// actions.yaml is permitted to point "$ref" or "$schema" at. Anything else
+// is rejected, since juju has no way to fetch or trust arbitrary remote
+// schemas at validation time.
+var whitelistedSchemaRefs = map[string]bool{
+	"http://json-schema.org/draft-07/schema#": true,
+	"http://json-schema.org/draft-04/schema#": true,
+}
+
 var actionNameRule = regexp.MustCompile("^[a-z](?:[a-z-]*[a-z])?$")
 
 // Actions defines the available actions for the charm.  Additional params
@@ -26,8 +34,8 @@ type Actions struct {
 }
 
 // ActionSpec is a definition of the parameters and traits of an Action.
-// The Params map is expected to conform to JSON-Schema Draft 4 as defined at
-// http://json-schema.org/draft-04/schema# (see http://json-schema.org/latest/json-schema-core.html)
+// The Params map is expected to conform to JSON-Schema Draft 7 as defined at
+// http://json-schema.org/draft-07/schema# (see http://json-schema.org/latest/json-schema-core.html)
 type ActionSpec struct {
 	Description string
 	Params      map[string]interface{}
@@ -38,25 +46,31 @@ func NewActions() *Actions {
 }
 
 // ValidateParams tells us whether an unmarshaled JSON object conforms to the
-// Params for the specific ActionSpec.
+// Params for the specific ActionSpec. If the params do not validate, the
+// returned error is a ValidationErrors value describing every failure.
 // Usage: ok, err := ch.Actions()["snapshot"].ValidateParams(jsonParams)
 func (spec *ActionSpec) ValidateParams(params interface{}) (bool, error) {
 
-	specSchemaDoc, err := gojsonschema.NewJsonSchemaDocument(spec.Params)
+	schemaLoader := gojsonschema.NewGoLoader(spec.Params)
+	docLoader := gojsonschema.NewGoLoader(params)
+
+	result, err := gojsonschema.Validate(schemaLoader, docLoader)
 	if err != nil {
 		return false, err
 	}
-
-	results := specSchemaDoc.Validate(params)
-	if results.Valid() {
+	if result.Valid() {
 		return true, nil
 	}
 
-	var errorStrings []string
-	for _, validationError := range results.Errors() {
-		errorStrings = append(errorStrings, validationError.String())
+	var validationErrors ValidationErrors
+	for _, resultError := range result.Errors() {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   resultError.Field(),
+			Keyword: resultError.Type(),
+			Message: resultError.Description(),
+		})
 	}
-	return false, fmt.Errorf("JSON validation failed: %s", strings.Join(errorStrings, "; "))
+	return false, validationErrors
 }
 
 // ReadActions builds an Actions spec from a charm's actions.yaml.
@@ -89,6 +103,13 @@ func ReadActionsYaml(r io.Reader) (*Actions, error) {
 		}
 
 		for key, value := range actionSpec {
+			if prohibitedSchemaKeys[key] {
+				ref, ok := value.(string)
+				if !ok || !whitelistedSchemaRefs[ref] {
+					return nil, fmt.Errorf("schema key %q not compatible with this version of juju", key)
+				}
+			}
+
 			switch key {
 			case "description":
 				// These fields must be strings.
@@ -136,8 +157,8 @@ func ReadActionsYaml(r io.Reader) (*Actions, error) {
 		}
 
 		// Make sure the new Params doc conforms to JSON-Schema
-		// Draft 4 (http://json-schema.org/latest/json-schema-core.html)
-		_, err = gojsonschema.NewJsonSchemaDocument(thisActionSchema)
+		// Draft 7 (http://json-schema.org/latest/json-schema-core.html)
+		_, err = gojsonschema.NewSchema(gojsonschema.NewGoLoader(thisActionSchema))
 		if err != nil {
 			return nil, errors.Annotatef(err, "invalid params schema for action schema %s", name)
 		}
@@ -151,8 +172,9 @@ func ReadActionsYaml(r io.Reader) (*Actions, error) {
 	return result, nil
 }
 
-// cleanse rejects schemas containing references or maps keyed with non-
-// strings, and coerces acceptable maps to contain only maps with string keys.
+// cleanse rejects schemas containing references to anything other than a
+// whitelisted well-known schema, or maps keyed with non-strings, and
+// coerces acceptable maps to contain only maps with string keys.
 func cleanse(input interface{}) (interface{}, error) {
 	switch typedInput := input.(type) {
 
@@ -162,7 +184,10 @@ func cleanse(input interface{}) (interface{}, error) {
 		for key, value := range typedInput {
 
 			if prohibitedSchemaKeys[key] {
-				return nil, fmt.Errorf("schema key %q not compatible with this version of juju", key)
+				ref, ok := value.(string)
+				if !ok || !whitelistedSchemaRefs[ref] {
+					return nil, fmt.Errorf("schema key %q not compatible with this version of juju", key)
+				}
 			}
 
 			newValue, err := cleanse(value)
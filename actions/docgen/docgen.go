@@ -0,0 +1,142 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package docgen generates example action invocations and an OpenAPI
+// document from a charm's Actions, so charm authors don't have to
+// hand-maintain either alongside actions.yaml.
+package docgen
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/charm"
+)
+
+// Examples synthesizes a minimally-valid example params document for every
+// action in a, by walking each action's JSON-Schema properties and
+// honouring "default", "enum", "example", and "type" where present.
+func Examples(a *charm.Actions) (map[string]json.RawMessage, error) {
+	result := make(map[string]json.RawMessage, len(a.ActionSpecs))
+	for name, spec := range a.ActionSpecs {
+		raw, err := json.Marshal(exampleObject(spec.Params))
+		if err != nil {
+			return nil, errors.Annotatef(err, "marshalling example for action %q", name)
+		}
+		result[name] = json.RawMessage(raw)
+	}
+	return result, nil
+}
+
+// Info holds the OpenAPI document metadata that isn't derivable from an
+// Actions value and so must be supplied by the caller.
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// OpenAPI renders a as an OpenAPI 3.0 document, with one operation under
+// "/actions/{name}" per action and its params schema inlined as the
+// request body.
+func OpenAPI(a *charm.Actions, info Info) ([]byte, error) {
+	paths := make(map[string]interface{}, len(a.ActionSpecs))
+	for name, spec := range a.ActionSpecs {
+		paths[fmt.Sprintf("/actions/%s", name)] = map[string]interface{}{
+			"post": map[string]interface{}{
+				"operationId": name,
+				"summary":     spec.Description,
+				"requestBody": map[string]interface{}{
+					"required": true,
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": spec.Params,
+						},
+					},
+				},
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "the action was queued",
+					},
+				},
+			},
+		}
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":       info.Title,
+			"version":     info.Version,
+			"description": info.Description,
+		},
+		"paths": paths,
+	}
+
+	raw, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, errors.Annotate(err, "marshalling OpenAPI document")
+	}
+	return raw, nil
+}
+
+// exampleObject builds a minimally-valid example instance for an action's
+// params schema: only the properties listed in "required" are populated.
+func exampleObject(schema map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if reqList, ok := schema["required"].([]interface{}); ok {
+		for _, r := range reqList {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	for name := range required {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result[name] = exampleValue(prop)
+	}
+	return result
+}
+
+// exampleValue produces a single minimally-valid example for a property
+// schema, preferring an explicit "example", then "default", then the first
+// "enum" value, and finally falling back to a zero value for "type".
+func exampleValue(schema map[string]interface{}) interface{} {
+	if v, ok := schema["example"]; ok {
+		return v
+	}
+	if v, ok := schema["default"]; ok {
+		return v
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	switch schema["type"] {
+	case "object":
+		return exampleObject(schema)
+	case "array":
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return []interface{}{}
+		}
+		return []interface{}{exampleValue(items)}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		return ""
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package docgen
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/juju/charm"
+)
+
+func actionsWithParams(params map[string]interface{}) *charm.Actions {
+	return &charm.Actions{
+		ActionSpecs: map[string]charm.ActionSpec{
+			"snapshot": {
+				Description: "Take a snapshot.",
+				Params:      params,
+			},
+		},
+	}
+}
+
+func exampleFor(t *testing.T, params map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	examples, err := Examples(actionsWithParams(params))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, ok := examples["snapshot"]
+	if !ok {
+		t.Fatalf("expected an example for %q, got %#v", "snapshot", examples)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unexpected error unmarshalling example: %v", err)
+	}
+	return got
+}
+
+func TestExamplesRequiredScalarProperties(t *testing.T) {
+	got := exampleFor(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"outfile": map[string]interface{}{"type": "string"},
+			"retries": map[string]interface{}{"type": "integer"},
+			"force":   map[string]interface{}{"type": "boolean"},
+			"unused":  map[string]interface{}{"type": "string"},
+		},
+		"required": []interface{}{"outfile", "retries", "force"},
+	})
+
+	want := map[string]interface{}{
+		"outfile": "",
+		"retries": float64(0),
+		"force":   false,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestExamplesPrefersExampleOverDefault(t *testing.T) {
+	got := exampleFor(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"outfile": map[string]interface{}{
+				"type":    "string",
+				"default": "default.tgz",
+				"example": "example.tgz",
+			},
+		},
+		"required": []interface{}{"outfile"},
+	})
+
+	if got["outfile"] != "example.tgz" {
+		t.Errorf("got %#v, want %q", got["outfile"], "example.tgz")
+	}
+}
+
+func TestExamplesUsesDefaultWhenNoExample(t *testing.T) {
+	got := exampleFor(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"outfile": map[string]interface{}{
+				"type":    "string",
+				"default": "default.tgz",
+			},
+		},
+		"required": []interface{}{"outfile"},
+	})
+
+	if got["outfile"] != "default.tgz" {
+		t.Errorf("got %#v, want %q", got["outfile"], "default.tgz")
+	}
+}
+
+func TestExamplesUsesFirstEnumWhenNoExampleOrDefault(t *testing.T) {
+	got := exampleFor(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"level": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"low", "medium", "high"},
+			},
+		},
+		"required": []interface{}{"level"},
+	})
+
+	if got["level"] != "low" {
+		t.Errorf("got %#v, want %q", got["level"], "low")
+	}
+}
+
+func TestExamplesRecursesIntoNestedObjectAndArray(t *testing.T) {
+	got := exampleFor(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"target": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"host": map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"host"},
+			},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []interface{}{"target", "tags"},
+	})
+
+	want := map[string]interface{}{
+		"target": map[string]interface{}{"host": ""},
+		"tags":   []interface{}{""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestOpenAPIPathAndInlinedSchema(t *testing.T) {
+	params := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"outfile": map[string]interface{}{"type": "string"},
+		},
+	}
+	raw, err := OpenAPI(actionsWithParams(params), Info{
+		Title:   "Test charm",
+		Version: "1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("unexpected error unmarshalling OpenAPI document: %v", err)
+	}
+
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a paths object, got %#v", doc["paths"])
+	}
+	action, ok := paths["/actions/snapshot"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a %q path, got %#v", "/actions/snapshot", paths)
+	}
+
+	post, ok := action["post"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a post operation, got %#v", action)
+	}
+	if post["operationId"] != "snapshot" {
+		t.Errorf("got operationId %#v, want %q", post["operationId"], "snapshot")
+	}
+
+	requestBody, ok := post["requestBody"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a requestBody, got %#v", post)
+	}
+	content, ok := requestBody["content"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected content, got %#v", requestBody)
+	}
+	body, ok := content["application/json"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected application/json content, got %#v", content)
+	}
+	schema, ok := body["schema"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected an inlined schema, got %#v", body)
+	}
+	if schema["type"] != "object" {
+		t.Errorf("got inlined schema type %#v, want %q", schema["type"], "object")
+	}
+}
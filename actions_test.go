@@ -0,0 +1,102 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadActionsYamlValid(t *testing.T) {
+	reader := strings.NewReader(`
+snapshot:
+  description: Take a snapshot.
+  params:
+    outfile:
+      type: string
+  required: [outfile]
+`)
+	actions, err := ReadActionsYaml(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec, ok := actions.ActionSpecs["snapshot"]
+	if !ok {
+		t.Fatalf("expected a %q action", "snapshot")
+	}
+	if spec.Description != "Take a snapshot." {
+		t.Errorf("got description %q", spec.Description)
+	}
+}
+
+func TestReadActionsYamlWhitelistedSchemaRef(t *testing.T) {
+	reader := strings.NewReader(`
+snapshot:
+  description: Take a snapshot.
+  $schema: http://json-schema.org/draft-07/schema#
+  params:
+    outfile:
+      type: string
+`)
+	if _, err := ReadActionsYaml(reader); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReadActionsYamlRejectsTopLevelSchemaRef(t *testing.T) {
+	// A "$schema" sibling of "description"/"params" must be checked against
+	// the whitelist just like one nested inside "params" is.
+	reader := strings.NewReader(`
+snapshot:
+  description: Take a snapshot.
+  $schema: http://attacker.example/schema
+  params:
+    outfile:
+      type: string
+`)
+	if _, err := ReadActionsYaml(reader); err == nil {
+		t.Fatal("expected an error for a non-whitelisted top-level $schema")
+	}
+}
+
+func TestReadActionsYamlRejectsNestedSchemaRef(t *testing.T) {
+	reader := strings.NewReader(`
+snapshot:
+  description: Take a snapshot.
+  params:
+    outfile:
+      $ref: http://attacker.example/schema
+`)
+	if _, err := ReadActionsYaml(reader); err == nil {
+		t.Fatal("expected an error for a non-whitelisted nested $ref")
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	reader := strings.NewReader(`
+snapshot:
+  description: Take a snapshot.
+  params:
+    outfile:
+      type: string
+  required: [outfile]
+`)
+	actions, err := ReadActionsYaml(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := actions.ActionSpecs["snapshot"]
+
+	if ok, err := spec.ValidateParams(map[string]interface{}{"outfile": "out.tgz"}); !ok {
+		t.Fatalf("expected valid params, got error: %v", err)
+	}
+
+	ok, err := spec.ValidateParams(map[string]interface{}{})
+	if ok {
+		t.Fatal("expected missing required param to be invalid")
+	}
+	if _, isValidationErrors := err.(ValidationErrors); !isValidationErrors {
+		t.Fatalf("expected a ValidationErrors, got %T", err)
+	}
+}
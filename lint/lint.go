@@ -0,0 +1,330 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lint validates a charm's actions.yaml and the resource blocks in
+// its metadata.yaml against the schemas built by charm.ReadActionsYaml and
+// resource.ParseMeta, and reports the problems those functions have no way
+// to catch on their own: unreachable "required" keys, duplicate names that
+// differ only in case, colliding resource filenames, and unrecognized keys.
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/juju/charm"
+	"github.com/juju/charm/resource"
+)
+
+// Severity classifies how serious an Issue is.
+type Severity int
+
+const (
+	// Error indicates the document will be rejected by ReadActionsYaml,
+	// resource.ParseMeta, or resource.Meta.Validate, or is inconsistent in
+	// a way they do not detect (e.g. a required param that is not declared,
+	// or two resources sharing a filename).
+	Error Severity = iota
+	// Warning indicates the document will be accepted, but is likely a
+	// mistake.
+	Warning
+)
+
+// String returns the printable representation of the severity.
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+// Issue describes a single problem found while linting a charm document.
+type Issue struct {
+	// File is the path of the document the issue was found in.
+	File string
+	// Line is the 1-based line the issue applies to, or 0 if unknown.
+	Line int
+	// Column is the 1-based column the issue applies to, or 0 if unknown.
+	Column int
+	// Severity classifies how serious the issue is.
+	Severity Severity
+	// Rule is the id of the check that produced the issue.
+	Rule string
+	// Message is a human-readable description of the issue.
+	Message string
+}
+
+// String returns the issue in "file:line:column: severity: rule: message"
+// form, suitable for printing on a CLI.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s: %s", i.File, i.Line, i.Column, i.Severity, i.Rule, i.Message)
+}
+
+// knownActionKeys are the keys ReadActionsYaml special-cases within a single
+// action's spec ("$schema"/"$ref" are accepted only when they point at a
+// whitelisted well-known schema, which ReadActionsYaml itself enforces).
+// Anything else is passed through silently today, which is usually a typo.
+var knownActionKeys = map[string]bool{
+	"description": true,
+	"title":       true,
+	"params":      true,
+	"required":    true,
+	"$schema":     true,
+	"$ref":        true,
+}
+
+// Directory walks path, linting every actions.yaml and metadata.yaml it
+// finds.
+func Directory(path string) ([]Issue, error) {
+	var issues []Issue
+	err := filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		switch info.Name() {
+		case "actions.yaml", "metadata.yaml":
+		default:
+			return nil
+		}
+		found, err := File(p)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "linting %s", path)
+	}
+	return issues, nil
+}
+
+// File lints a single actions.yaml or metadata.yaml, dispatching on its
+// base name.
+func File(path string) ([]Issue, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	switch filepath.Base(path) {
+	case "actions.yaml":
+		return lintActions(path, data)
+	case "metadata.yaml":
+		return lintMetadata(path, data)
+	default:
+		return nil, errors.Errorf("%s: not an actions.yaml or metadata.yaml", path)
+	}
+}
+
+func lintActions(path string, data []byte) ([]Issue, error) {
+	var issues []Issue
+
+	// Let charm.ReadActionsYaml do the authoritative parse, so every issue
+	// it would reject is surfaced the same way callers already expect.
+	if _, err := charm.ReadActionsYaml(strings.NewReader(string(data))); err != nil {
+		issues = append(issues, Issue{
+			File:     path,
+			Severity: Error,
+			Rule:     "invalid-actions-yaml",
+			Message:  err.Error(),
+		})
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return issues, nil
+	}
+	root := documentRoot(&doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return issues, nil
+	}
+
+	seenNames := map[string]string{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		nameNode, specNode := root.Content[i], root.Content[i+1]
+		name := nameNode.Value
+
+		if lower := strings.ToLower(name); seenNames[lower] != "" && seenNames[lower] != name {
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     nameNode.Line,
+				Column:   nameNode.Column,
+				Severity: Error,
+				Rule:     "duplicate-action-name",
+				Message:  fmt.Sprintf("action %q differs only in case from %q", name, seenNames[lower]),
+			})
+		} else {
+			seenNames[lower] = name
+		}
+
+		issues = append(issues, lintActionSpec(path, name, specNode)...)
+	}
+	return issues, nil
+}
+
+func lintActionSpec(path, name string, specNode *yaml.Node) []Issue {
+	if specNode.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var issues []Issue
+	var required []*yaml.Node
+	declared := map[string]bool{}
+
+	for i := 0; i+1 < len(specNode.Content); i += 2 {
+		keyNode, valueNode := specNode.Content[i], specNode.Content[i+1]
+		key := keyNode.Value
+
+		if !knownActionKeys[key] {
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     keyNode.Line,
+				Column:   keyNode.Column,
+				Severity: Warning,
+				Rule:     "unknown-action-key",
+				Message:  fmt.Sprintf("action %q has unrecognized key %q", name, key),
+			})
+		}
+
+		switch key {
+		case "required":
+			if valueNode.Kind == yaml.SequenceNode {
+				required = append(required, valueNode.Content...)
+			}
+		case "params":
+			if valueNode.Kind == yaml.MappingNode {
+				for j := 0; j+1 < len(valueNode.Content); j += 2 {
+					declared[valueNode.Content[j].Value] = true
+				}
+			}
+		}
+	}
+
+	for _, req := range required {
+		if !declared[req.Value] {
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     req.Line,
+				Column:   req.Column,
+				Severity: Error,
+				Rule:     "required-undeclared",
+				Message:  fmt.Sprintf("action %q requires param %q, which is not declared in params", name, req.Value),
+			})
+		}
+	}
+
+	return issues
+}
+
+func lintMetadata(path string, data []byte) ([]Issue, error) {
+	var issues []Issue
+
+	var unmarshaled map[string]interface{}
+	if err := yaml.Unmarshal(data, &unmarshaled); err != nil {
+		return nil, errors.Annotatef(err, "%s", path)
+	}
+
+	resources, ok := unmarshaled["resources"].(map[string]interface{})
+	if !ok {
+		return issues, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return issues, nil
+	}
+	resourcesNode := findMappingValue(documentRoot(&doc), "resources")
+
+	seenFilenames := map[string]string{}
+	for name, raw := range resources {
+		if raw != nil {
+			if _, ok := raw.(map[string]interface{}); !ok {
+				issues = append(issues, Issue{
+					File:     path,
+					Line:     lineOf(resourcesNode, name),
+					Severity: Error,
+					Rule:     "invalid-resource",
+					Message:  fmt.Sprintf("resource %q: must be a map, got %T", name, raw),
+				})
+				continue
+			}
+		}
+
+		meta := resource.ParseMeta(name, raw)
+		if err := meta.Validate(); err != nil {
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     lineOf(resourcesNode, name),
+				Severity: Error,
+				Rule:     "invalid-resource",
+				Message:  fmt.Sprintf("resource %q: %s", name, err),
+			})
+			continue
+		}
+
+		if owner, ok := seenFilenames[meta.Path]; ok {
+			issues = append(issues, Issue{
+				File:     path,
+				Line:     lineOf(resourcesNode, name),
+				Severity: Error,
+				Rule:     "duplicate-resource-filename",
+				Message:  fmt.Sprintf("resource %q and %q both use filename %q", owner, name, meta.Path),
+			})
+		} else if meta.Path != "" {
+			seenFilenames[meta.Path] = name
+		}
+	}
+
+	return issues, nil
+}
+
+// documentRoot unwraps a yaml.Node read via Unmarshal, which always
+// produces a DocumentNode wrapping the real root.
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// findMappingValue returns the value node for key within a mapping node, or
+// nil if root isn't a mapping or doesn't contain key.
+func findMappingValue(root *yaml.Node, key string) *yaml.Node {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			return root.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lineOf returns the line number of key within a mapping node, or 0 if it
+// can't be found.
+func lineOf(mapping *yaml.Node, key string) int {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return 0
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i].Line
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+// Copyright 2011, 2012, 2013 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func lintMetadataString(t *testing.T, yaml string) []Issue {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "lint-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "metadata.yaml")
+	if err := ioutil.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issues, err := File(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return issues
+}
+
+func TestFileMetadataMalformedResourceFieldDoesNotPanic(t *testing.T) {
+	// A non-string "type" used to reach resource.ParseMeta's unchecked
+	// val.(string) assertion and panic the whole lint run.
+	issues := lintMetadataString(t, `
+name: test
+resources:
+  myres:
+    type: 123
+    filename: myres.tgz
+`)
+
+	found := false
+	for _, issue := range issues {
+		if issue.Rule == "invalid-resource" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an invalid-resource issue, got %#v", issues)
+	}
+}
+
+func TestLintActionsUnknownKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lint-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "actions.yaml")
+	err = ioutil.WriteFile(path, []byte(`
+snapshot:
+  description: Take a snapshot.
+  retry: true
+`), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issues, err := File(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := false
+	for _, issue := range issues {
+		if issue.Rule == "unknown-action-key" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an unknown-action-key issue, got %#v", issues)
+	}
+}
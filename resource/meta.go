@@ -5,7 +5,6 @@ package resource
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/juju/errors"
 )
@@ -32,6 +31,27 @@ type Meta struct {
 
 	// Comment holds optional user-facing info for the resource.
 	Comment string
+
+	// Extra holds the type-specific metadata keys parsed by the resource's
+	// Type (e.g. "image" for an oci-image, "hash" for a url). It is nil for
+	// types that declare no extra keys.
+	Extra map[string]interface{}
+
+	// extraErr records an error parsing Extra's type-specific keys (e.g. a
+	// malformed "hash:"), so Validate can reject it.
+	extraErr error
+
+	// parseErr records a malformed top-level field (e.g. "type" given as a
+	// number rather than a string), so Validate can reject it.
+	parseErr error
+}
+
+// Fetched reports whether juju itself fetches this resource (e.g. an
+// "oci-image" or "url"), as opposed to the charm author uploading it
+// directly (e.g. a "file").
+func (meta Meta) Fetched() bool {
+	spec, ok := meta.Type.spec()
+	return ok && spec.Fetched
 }
 
 // ParseMeta parses the provided data into a Meta.
@@ -42,18 +62,46 @@ func ParseMeta(name string, data interface{}) Meta {
 	if data == nil {
 		return meta
 	}
-	rMap := data.(map[string]interface{})
+	rMap, ok := data.(map[string]interface{})
+	if !ok {
+		meta.parseErr = errors.Errorf("resource metadata must be a mapping, got %T", data)
+		return meta
+	}
 
 	if val := rMap["type"]; val != nil {
-		meta.Type, _ = ParseType(val.(string))
+		s, ok := val.(string)
+		if !ok {
+			meta.parseErr = errors.Errorf("resource type must be a string, got %T", val)
+			return meta
+		}
+		meta.Type, _ = ParseType(s)
 	}
 
 	if val := rMap["filename"]; val != nil {
-		meta.Path = val.(string)
+		s, ok := val.(string)
+		if !ok {
+			meta.parseErr = errors.Errorf("resource filename must be a string, got %T", val)
+			return meta
+		}
+		meta.Path = s
 	}
 
 	if val := rMap["comment"]; val != nil {
-		meta.Comment = val.(string)
+		s, ok := val.(string)
+		if !ok {
+			meta.parseErr = errors.Errorf("resource comment must be a string, got %T", val)
+			return meta
+		}
+		meta.Comment = s
+	}
+
+	if spec, ok := meta.Type.spec(); ok && spec.ParseExtra != nil {
+		extra, err := spec.ParseExtra(rMap)
+		if err != nil {
+			meta.extraErr = err
+		} else if len(extra) > 0 {
+			meta.Extra = extra
+		}
 	}
 
 	return meta
@@ -61,6 +109,11 @@ func ParseMeta(name string, data interface{}) Meta {
 
 // Validate checks the resource metadata to ensure the data is valid.
 func (meta Meta) Validate() error {
+	if meta.parseErr != nil {
+		msg := fmt.Sprintf("invalid metadata for resource %q: %v", meta.Name, meta.parseErr)
+		return errors.NewNotValid(nil, msg)
+	}
+
 	if meta.Name == "" {
 		return errors.NewNotValid(nil, "resource missing name")
 	}
@@ -77,13 +130,17 @@ func (meta Meta) Validate() error {
 		// TODO(ericsnow) change "filename" to "path"
 		return errors.NewNotValid(nil, "resource missing filename")
 	}
-	if meta.Type == TypeFile {
-		if strings.Contains(meta.Path, "/") {
-			msg := fmt.Sprintf(`filename cannot contain "/" (got %q)`, meta.Path)
+	if spec, ok := meta.Type.spec(); ok && spec.ValidatePath != nil {
+		if err := spec.ValidatePath(meta.Path); err != nil {
+			msg := fmt.Sprintf("invalid filename %q: %v", meta.Path, err)
 			return errors.NewNotValid(nil, msg)
 		}
-		// TODO(ericsnow) Constrain Path to alphanumeric?
+	}
+
+	if meta.extraErr != nil {
+		msg := fmt.Sprintf("invalid metadata for resource %q: %v", meta.Name, meta.extraErr)
+		return errors.NewNotValid(nil, msg)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
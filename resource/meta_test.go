@@ -0,0 +1,26 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package resource
+
+import "testing"
+
+func TestParseMetaMalformedTypeDoesNotPanic(t *testing.T) {
+	meta := ParseMeta("myres", map[string]interface{}{
+		"type":     123,
+		"filename": "myres.tgz",
+	})
+	if err := meta.Validate(); err == nil {
+		t.Fatal("expected Validate to reject a non-string type")
+	}
+}
+
+func TestParseMetaMalformedFilenameDoesNotPanic(t *testing.T) {
+	meta := ParseMeta("myres", map[string]interface{}{
+		"type":     "file",
+		"filename": 123,
+	})
+	if err := meta.Validate(); err == nil {
+		t.Fatal("expected Validate to reject a non-string filename")
+	}
+}
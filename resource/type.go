@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package resource
+
+import (
+	"github.com/juju/errors"
+)
+
+// Type enumerates the recognized resource types. The set of valid types is
+// not fixed: additional types may be added at runtime with RegisterType.
+type Type int
+
+// TypeUnknown is the zero Type, used for a Meta whose "type" key hasn't
+// been set (or doesn't match any registered type).
+const TypeUnknown Type = 0
+
+// TypeSpec defines how RegisterType teaches the resource package about a
+// new kind of resource, so that Meta, ParseMeta, and Validate don't need to
+// know about each type directly.
+type TypeSpec struct {
+	// Fetched reports whether juju itself fetches a resource of this type
+	// (true, e.g. "oci-image", "url"), as opposed to the charm author
+	// uploading it directly (false, e.g. "file").
+	Fetched bool
+
+	// ValidatePath validates a resource's Path for this type, returning a
+	// descriptive error if it is invalid.
+	ValidatePath func(path string) error
+
+	// ParseExtra parses any extra, type-specific keys out of a resource's
+	// raw metadata map (e.g. "image", "url", "hash") into the string-keyed
+	// map stashed on Meta.Extra. It may be nil if the type has no extra
+	// keys.
+	ParseExtra func(raw map[string]interface{}) (map[string]interface{}, error)
+}
+
+var (
+	typeSpecs   = map[Type]TypeSpec{}
+	typeNames   = map[Type]string{}
+	typesByName = map[string]Type{}
+	nextType    = TypeUnknown + 1
+)
+
+// RegisterType adds a new resource type to the registry, returning the Type
+// value callers should use to refer to it. Registering a name that is
+// already registered replaces its spec in place, leaving the existing Type
+// value (and anything already parsed with it) valid.
+func RegisterType(name string, spec TypeSpec) Type {
+	if t, ok := typesByName[name]; ok {
+		typeSpecs[t] = spec
+		return t
+	}
+
+	t := nextType
+	nextType++
+	typesByName[name] = t
+	typeNames[t] = name
+	typeSpecs[t] = spec
+	return t
+}
+
+// ParseType converts a string to a Type. If the given value does not match
+// a registered type then an error is returned. An empty value is treated as
+// "file", for backwards compatibility with metadata that predates the
+// "type" key.
+func ParseType(value string) (Type, error) {
+	given := value
+	if value == "" {
+		value = typeNames[TypeFile]
+	}
+
+	if t, ok := typesByName[value]; ok {
+		return t, nil
+	}
+	return TypeUnknown, errors.NotValidf("resource type %q", given)
+}
+
+// String returns the printable representation of the type.
+func (rt Type) String() string {
+	return typeNames[rt]
+}
+
+// Validate ensures that the type is registered.
+func (rt Type) Validate() error {
+	if _, ok := typeNames[rt]; !ok {
+		return errors.NewNotValid(nil, "unknown resource type")
+	}
+	return nil
+}
+
+// spec returns the TypeSpec rt was registered with, and whether rt is
+// actually registered.
+func (rt Type) spec() (TypeSpec, bool) {
+	spec, ok := typeSpecs[rt]
+	return spec, ok
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package resource
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// TypeFile is a resource uploaded directly by the charm author and stored
+// by juju as an opaque file.
+var TypeFile = RegisterType("file", TypeSpec{
+	Fetched: false,
+	ValidatePath: func(path string) error {
+		if strings.Contains(path, "/") {
+			return errors.Errorf(`filename cannot contain "/" (got %q)`, path)
+		}
+		return nil
+	},
+})
+
+// TypeOCIImage is a resource backed by an OCI image reference that juju
+// fetches from a registry on the charm's behalf.
+var TypeOCIImage = RegisterType("oci-image", TypeSpec{
+	Fetched: true,
+	ValidatePath: func(path string) error {
+		if !ociReferenceRe.MatchString(path) {
+			return errors.Errorf("not a valid OCI image reference: %q", path)
+		}
+		return nil
+	},
+	ParseExtra: func(raw map[string]interface{}) (map[string]interface{}, error) {
+		extra := map[string]interface{}{}
+		if image, ok := raw["image"]; ok {
+			extra["image"] = image
+		}
+		return extra, nil
+	},
+})
+
+// TypeURL is a resource backed by an external http(s) URL that juju fetches
+// on the charm's behalf, optionally pinned to a sha256 hash.
+var TypeURL = RegisterType("url", TypeSpec{
+	Fetched: true,
+	ValidatePath: func(path string) error {
+		parsed, err := url.Parse(path)
+		if err != nil {
+			return errors.Annotatef(err, "invalid URL %q", path)
+		}
+		if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			return errors.Errorf("URL %q must use http or https", path)
+		}
+		return nil
+	},
+	ParseExtra: func(raw map[string]interface{}) (map[string]interface{}, error) {
+		extra := map[string]interface{}{}
+		if hash, ok := raw["hash"]; ok {
+			hashStr, ok := hash.(string)
+			if !ok || !sha256Re.MatchString(hashStr) {
+				return nil, fmt.Errorf("hash must be a sha256 hex digest, got %v", hash)
+			}
+			extra["hash"] = hashStr
+		}
+		return extra, nil
+	},
+})
+
+// ociReferenceRe matches an OCI image reference of the form
+// "[host[:port]/]repo[:tag][@digest]", e.g. "ubuntu:20.04",
+// "ubuntu@sha256:abcdef0123...", or "localhost:5000/myimage:latest".
+var ociReferenceRe = regexp.MustCompile(
+	`^(?:[a-z0-9]+(?:[.-][a-z0-9]+)*(?::\d+)?/)?` +
+		`[a-z0-9]+(?:[._-][a-z0-9]+)*(?:/[a-z0-9]+(?:[._-][a-z0-9]+)*)*` +
+		`(?::[A-Za-z0-9_][A-Za-z0-9._-]{0,127})?` +
+		`(?:@[A-Za-z0-9]+(?:[+._-][A-Za-z0-9]+)*:[A-Fa-f0-9]{32,})?$`,
+)
+
+var sha256Re = regexp.MustCompile(`^[A-Fa-f0-9]{64}$`)
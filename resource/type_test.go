@@ -0,0 +1,148 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package resource
+
+import "testing"
+
+func TestRegisterTypeAndParseTypeRoundTrip(t *testing.T) {
+	registered := RegisterType("test-widget", TypeSpec{Fetched: true})
+
+	parsed, err := ParseType("test-widget")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed != registered {
+		t.Errorf("got %v, want %v", parsed, registered)
+	}
+	if parsed.String() != "test-widget" {
+		t.Errorf("got String() %q, want %q", parsed.String(), "test-widget")
+	}
+	spec, ok := parsed.spec()
+	if !ok || !spec.Fetched {
+		t.Errorf("got spec %#v, ok=%v; want Fetched=true", spec, ok)
+	}
+}
+
+func TestRegisterTypeReRegistrationKeepsSameType(t *testing.T) {
+	first := RegisterType("test-widget-rereg", TypeSpec{Fetched: false})
+	second := RegisterType("test-widget-rereg", TypeSpec{Fetched: true})
+
+	if first != second {
+		t.Errorf("re-registering %q changed its Type value: %v != %v", "test-widget-rereg", first, second)
+	}
+	spec, ok := second.spec()
+	if !ok || !spec.Fetched {
+		t.Errorf("expected the replaced spec to take effect, got %#v", spec)
+	}
+}
+
+func TestParseTypeUnknown(t *testing.T) {
+	if _, err := ParseType("does-not-exist"); err == nil {
+		t.Fatal("expected an error for an unregistered type")
+	}
+}
+
+func TestParseTypeEmptyDefaultsToFile(t *testing.T) {
+	parsed, err := ParseType("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed != TypeFile {
+		t.Errorf("got %v, want TypeFile", parsed)
+	}
+}
+
+func TestOCIImageValidatePath(t *testing.T) {
+	spec, ok := TypeOCIImage.spec()
+	if !ok {
+		t.Fatal("TypeOCIImage not registered")
+	}
+
+	valid := []string{
+		"ubuntu",
+		"ubuntu:20.04",
+		"ubuntu@sha256:" + repeatHex(64),
+		"localhost:5000/myimage:latest",
+		"my-registry.example.com/team/app:v1",
+	}
+	for _, path := range valid {
+		if err := spec.ValidatePath(path); err != nil {
+			t.Errorf("ValidatePath(%q): unexpected error: %v", path, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"Ubuntu",
+		"ubuntu:",
+		"UPPERCASE/repo",
+	}
+	for _, path := range invalid {
+		if err := spec.ValidatePath(path); err == nil {
+			t.Errorf("ValidatePath(%q): expected an error, got nil", path)
+		}
+	}
+}
+
+func TestURLValidatePath(t *testing.T) {
+	spec, ok := TypeURL.spec()
+	if !ok {
+		t.Fatal("TypeURL not registered")
+	}
+
+	valid := []string{
+		"http://example.com/file.tgz",
+		"https://example.com/file.tgz",
+	}
+	for _, path := range valid {
+		if err := spec.ValidatePath(path); err != nil {
+			t.Errorf("ValidatePath(%q): unexpected error: %v", path, err)
+		}
+	}
+
+	invalid := []string{
+		"ftp://example.com/file.tgz",
+		"not a url",
+		"file:///etc/passwd",
+	}
+	for _, path := range invalid {
+		if err := spec.ValidatePath(path); err == nil {
+			t.Errorf("ValidatePath(%q): expected an error, got nil", path)
+		}
+	}
+}
+
+func TestURLHashFormatViaParseMetaAndValidate(t *testing.T) {
+	good := ParseMeta("blob", map[string]interface{}{
+		"type":     "url",
+		"filename": "https://example.com/file.tgz",
+		"hash":     repeatHex(64),
+	})
+	if err := good.Validate(); err != nil {
+		t.Errorf("unexpected error for a valid sha256 hash: %v", err)
+	}
+	if got := good.Extra["hash"]; got != repeatHex(64) {
+		t.Errorf("got Extra[hash] %#v, want %q", got, repeatHex(64))
+	}
+
+	bad := ParseMeta("blob", map[string]interface{}{
+		"type":     "url",
+		"filename": "https://example.com/file.tgz",
+		"hash":     "not-a-hash",
+	})
+	if err := bad.Validate(); err == nil {
+		t.Error("expected an error for a malformed hash")
+	}
+}
+
+// repeatHex returns a string of n lowercase hex digits, for building
+// syntactically valid digests/hashes without hardcoding long literals.
+func repeatHex(n int) string {
+	const digits = "0123456789abcdef"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = digits[i%len(digits)]
+	}
+	return string(b)
+}